@@ -0,0 +1,28 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package chain
+
+import (
+	"context"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/hypersdk/crypto"
+)
+
+// Action is a single state transition a Transaction bundles. Concrete VMs
+// (e.g. tokenvm) register their Action implementations with an
+// ActionRegistry so transactions can be (un)marshaled without reflection.
+type Action interface {
+	GetTypeID() uint8
+	Marshal(p *Packer)
+	MaxUnits(r Rules) (uint64, error)
+	Execute(
+		ctx context.Context,
+		r Rules,
+		db Database,
+		timestamp int64,
+		actor crypto.PublicKey,
+		txID ids.ID,
+	) (*Result, error)
+}
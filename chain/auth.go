@@ -0,0 +1,20 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package chain
+
+import (
+	"context"
+
+	"github.com/ava-labs/hypersdk/crypto"
+)
+
+// Auth authenticates the actor responsible for a Transaction's fees and
+// state changes.
+type Auth interface {
+	GetTypeID() uint8
+	Marshal(p *Packer)
+	MaxUnits(r Rules) uint64
+	Verify(ctx context.Context, msg []byte) error
+	Actor() crypto.PublicKey
+}
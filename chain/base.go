@@ -0,0 +1,70 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package chain
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ava-labs/avalanchego/ids"
+)
+
+var (
+	ErrInvalidConflict    = errors.New("invalid conflict target")
+	ErrConflictSuperseded = errors.New("transaction was superseded by a conflicting transaction")
+)
+
+// Base carries the fields every hypersdk transaction has regardless of which
+// Action/Auth it bundles.
+type Base struct {
+	Timestamp int64  `json:"timestamp"`
+	ChainID   ids.ID `json:"chainID"`
+	MaxFee    uint64 `json:"maxFee"`
+
+	// Conflicts lists tx IDs this transaction must not co-exist with once
+	// accepted. Once this tx lands, every ID here is treated as superseded:
+	// PreExecute rejects any later attempt to land it (see ConflictChecker).
+	Conflicts []ids.ID `json:"conflicts"`
+}
+
+func (b *Base) Marshal(p *Packer) {
+	p.PackUint64(uint64(b.Timestamp))
+	p.PackID(b.ChainID)
+	p.PackUint64(b.MaxFee)
+	p.PackUint16(uint16(len(b.Conflicts)))
+	for _, id := range b.Conflicts {
+		p.PackID(id)
+	}
+}
+
+func UnmarshalBase(p *Packer) (*Base, error) {
+	b := &Base{
+		Timestamp: int64(p.UnpackUint64()),
+		ChainID:   p.UnpackID(),
+		MaxFee:    p.UnpackUint64(),
+	}
+	count := p.UnpackUint16()
+	b.Conflicts = make([]ids.ID, count)
+	for i := range b.Conflicts {
+		b.Conflicts[i] = p.UnpackID()
+	}
+	if p.Err() != nil {
+		return nil, p.Err()
+	}
+	return b, nil
+}
+
+// Validate rejects a Conflicts list that names the empty ID (reserved for
+// genesis and can never be a real tx) or names the transaction's own ID.
+func (b *Base) Validate(txID ids.ID) error {
+	for _, conflictID := range b.Conflicts {
+		if conflictID == ids.Empty {
+			return fmt.Errorf("%w: conflict target cannot be empty", ErrInvalidConflict)
+		}
+		if conflictID == txID {
+			return fmt.Errorf("%w: %s conflicts with itself", ErrInvalidConflict, txID)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,29 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package chain
+
+import (
+	"context"
+
+	"github.com/ava-labs/avalanchego/ids"
+)
+
+// ConflictChecker lets a VM veto a transaction whose declared Conflicts
+// have already landed. chain is VM-agnostic and has no storage layout of
+// its own, so the check is wired by the VM at init time (see
+// examples/tokenvm/storage/wire.go) rather than called directly.
+var ConflictChecker func(ctx context.Context, db Database, id ids.ID) (bool, ids.ID, error)
+
+// ResultRecorder lets a VM persist a transaction's receipt (and the
+// conflicts it declared) once Execute completes. Wired the same way as
+// ConflictChecker.
+var ResultRecorder func(
+	ctx context.Context,
+	db Database,
+	txID ids.ID,
+	timestamp int64,
+	success bool,
+	units uint64,
+	conflicts []ids.ID,
+) error
@@ -0,0 +1,54 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package conformance
+
+import (
+	"encoding/xml"
+	"io"
+)
+
+// junitTestSuite mirrors the subset of the JUnit XML schema CI test
+// reporters expect (name, tests/failures counts, and one testcase per
+// vector).
+type junitTestSuite struct {
+	XMLName   xml.Name        `xml:"testsuite"`
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	TestCases []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name    string        `xml:"name,attr"`
+	Failure *junitFailure `xml:"failure,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+}
+
+// WriteJUnitReport renders [outcomes] as a JUnit XML report to [w], so the
+// conformance run can be surfaced in CI alongside `go test` output.
+func WriteJUnitReport(w io.Writer, outcomes []*Outcome) error {
+	suite := junitTestSuite{
+		Name:      "tokenvm-conformance",
+		Tests:     len(outcomes),
+		TestCases: make([]junitTestCase, 0, len(outcomes)),
+	}
+	for _, o := range outcomes {
+		tc := junitTestCase{Name: o.Vector.ID}
+		if !o.Passed() {
+			suite.Failures++
+			tc.Failure = &junitFailure{Message: o.Err.Error()}
+		}
+		suite.TestCases = append(suite.TestCases, tc)
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	return enc.Encode(suite)
+}
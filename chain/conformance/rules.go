@@ -0,0 +1,15 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package conformance
+
+import "github.com/ava-labs/hypersdk/chain"
+
+// fixedRules is a permissive chain.Rules used to replay a vector's single
+// Tx without needing a real VM's block-building limits.
+type fixedRules struct{}
+
+func (fixedRules) GetMaxBlockUnits() uint64 { return 1_800_000 }
+func (fixedRules) GetMaxBlockTxs() int      { return 10_000 }
+
+var _ chain.Rules = fixedRules{}
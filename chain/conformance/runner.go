@@ -0,0 +1,127 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package conformance
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"fmt"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/hypersdk/chain"
+)
+
+// Outcome is the result of replaying a single Vector.
+type Outcome struct {
+	Vector *Vector
+	Err    error // non-nil if the vector did not reproduce
+}
+
+// Passed reports whether the vector reproduced its expected post-state and
+// receipt exactly.
+func (o *Outcome) Passed() bool { return o.Err == nil }
+
+// Run replays every vector in [vectors] against an in-memory database
+// hydrated from its PreState, using the same decode/execute path the VM
+// takes in production (chain.UnmarshalTxs -> PreExecute -> Execute), and
+// diffs the resulting storage keys byte-for-byte against PostState.
+func Run(
+	ctx context.Context,
+	vectors []*Vector,
+	actionRegistry chain.ActionRegistry,
+	authRegistry chain.AuthRegistry,
+) ([]*Outcome, error) {
+	outcomes := make([]*Outcome, 0, len(vectors))
+	for _, v := range vectors {
+		outcomes = append(outcomes, runVector(ctx, v, actionRegistry, authRegistry))
+	}
+	return outcomes, nil
+}
+
+func runVector(
+	ctx context.Context,
+	v *Vector,
+	actionRegistry chain.ActionRegistry,
+	authRegistry chain.AuthRegistry,
+) *Outcome {
+	pre, err := decodeState(v.PreState)
+	if err != nil {
+		return &Outcome{Vector: v, Err: fmt.Errorf("decode preState: %w", err)}
+	}
+	post, err := decodeState(v.PostState)
+	if err != nil {
+		return &Outcome{Vector: v, Err: fmt.Errorf("decode postState: %w", err)}
+	}
+
+	rawTx, err := hex.DecodeString(v.Tx)
+	if err != nil {
+		return &Outcome{Vector: v, Err: fmt.Errorf("decode tx: %w", err)}
+	}
+	txs, err := chain.UnmarshalTxs(rawTx, 1, actionRegistry, authRegistry)
+	if err != nil {
+		return &Outcome{Vector: v, Err: fmt.Errorf("unmarshal tx: %w", err)}
+	}
+	if len(txs) != 1 {
+		return &Outcome{Vector: v, Err: fmt.Errorf("expected 1 tx in vector, got %d", len(txs))}
+	}
+	tx := txs[0]
+
+	db := newMemDB(pre)
+	r := fixedRules{}
+	ectx := chain.NewExecutionContext(ids.Empty, v.Timestamp)
+	if err := tx.PreExecute(ctx, ectx, r, db, v.Timestamp); err != nil {
+		return &Outcome{Vector: v, Err: fmt.Errorf("pre-execute: %w", err)}
+	}
+	result, err := tx.Execute(ctx, ectx, r, db, v.Timestamp)
+	if err != nil {
+		return &Outcome{Vector: v, Err: fmt.Errorf("execute: %w", err)}
+	}
+
+	if result.Success != v.Receipt.Success {
+		return &Outcome{Vector: v, Err: fmt.Errorf(
+			"receipt success mismatch: got %t, want %t", result.Success, v.Receipt.Success,
+		)}
+	}
+	if result.Units != v.Receipt.Units {
+		return &Outcome{Vector: v, Err: fmt.Errorf(
+			"receipt units mismatch: got %d, want %d", result.Units, v.Receipt.Units,
+		)}
+	}
+
+	got := db.dump()
+	if err := diffState(got, post); err != nil {
+		return &Outcome{Vector: v, Err: fmt.Errorf("post-state mismatch: %w", err)}
+	}
+	return &Outcome{Vector: v}
+}
+
+// diffState compares two sets of KV pairs (already sorted by key) and
+// returns an error describing the first divergence found.
+func diffState(got, want []KV) error {
+	gi, wi := 0, 0
+	for gi < len(got) && wi < len(want) {
+		switch bytes.Compare(got[gi].Key, want[wi].Key) {
+		case 0:
+			if !bytes.Equal(got[gi].Value, want[wi].Value) {
+				return fmt.Errorf(
+					"key %x: got %x, want %x", got[gi].Key, got[gi].Value, want[wi].Value,
+				)
+			}
+			gi++
+			wi++
+		case -1:
+			return fmt.Errorf("unexpected key %x present (value %x)", got[gi].Key, got[gi].Value)
+		default:
+			return fmt.Errorf("expected key %x missing (want value %x)", want[wi].Key, want[wi].Value)
+		}
+	}
+	if gi < len(got) {
+		return fmt.Errorf("unexpected key %x present (value %x)", got[gi].Key, got[gi].Value)
+	}
+	if wi < len(want) {
+		return fmt.Errorf("expected key %x missing (want value %x)", want[wi].Key, want[wi].Value)
+	}
+	return nil
+}
@@ -0,0 +1,113 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package conformance defines a shared test-vector format for validating
+// that a tokenvm state transition (pre-state + transaction -> post-state +
+// receipt) behaves identically across implementations and refactors.
+package conformance
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// Vector describes a single deterministic state transition: applying [Tx]
+// to [PreState] must produce exactly [PostState] and [Receipt].
+type Vector struct {
+	// ID uniquely identifies the vector within the corpus (e.g.
+	// "transfer/insufficient-balance"). Used for -skip filtering and
+	// report output.
+	ID string `json:"id"`
+
+	// Hardfork is the rule-set version this vector was authored against.
+	// The runner does not check it: every loaded vector is replayed
+	// against the same rules whatever its Hardfork.
+	Hardfork uint64 `json:"hardfork"`
+
+	// PreState/PostState map hex-encoded storage keys (as produced by the
+	// storage package's Prefix*Key helpers) to hex-encoded values. A key
+	// absent from PostState that was present in PreState is expected to
+	// have been deleted.
+	PreState  map[string]string `json:"preState"`
+	PostState map[string]string `json:"postState"`
+
+	// Tx is the hex-encoded, canonically marshaled transaction to replay,
+	// using the same wire format as chain.MarshalTxs/UnmarshalTxs.
+	Tx string `json:"tx"`
+
+	// Timestamp is the block timestamp (unix seconds) the tx is executed
+	// against, matching the [timestamp] argument to PreExecute/Execute.
+	Timestamp int64 `json:"timestamp"`
+
+	// Receipt is the expected outcome of executing Tx.
+	Receipt ExpectedReceipt `json:"receipt"`
+}
+
+// ExpectedReceipt is the subset of chain.Result the runner diffs against,
+// mirroring the fields storage.StoreTransaction persists.
+type ExpectedReceipt struct {
+	Success bool   `json:"success"`
+	Units   uint64 `json:"units"`
+}
+
+// KV is a single decoded (key, value) pair from a Vector's PreState or
+// PostState map.
+type KV struct {
+	Key   []byte
+	Value []byte
+}
+
+// decodeState converts a vector's hex-encoded state map into sorted KV pairs.
+func decodeState(state map[string]string) ([]KV, error) {
+	kvs := make([]KV, 0, len(state))
+	for k, v := range state {
+		key, err := hex.DecodeString(k)
+		if err != nil {
+			return nil, fmt.Errorf("invalid key %q: %w", k, err)
+		}
+		value, err := hex.DecodeString(v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value for key %q: %w", k, err)
+		}
+		kvs = append(kvs, KV{Key: key, Value: value})
+	}
+	sort.Slice(kvs, func(i, j int) bool {
+		return string(kvs[i].Key) < string(kvs[j].Key)
+	})
+	return kvs, nil
+}
+
+// LoadCorpus reads every "*.json" vector file under [dir], skipping any
+// vector whose ID appears in [skip].
+func LoadCorpus(dir string, skip map[string]bool) ([]*Vector, error) {
+	var vectors []*Vector
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() || filepath.Ext(path) != ".json" {
+			return nil
+		}
+		b, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+		v := new(Vector)
+		if err := json.Unmarshal(b, v); err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+		if skip[v.ID] {
+			return nil
+		}
+		vectors = append(vectors, v)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return vectors, nil
+}
@@ -0,0 +1,13 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package chain
+
+import "context"
+
+// Database is the state interface Actions read and write through.
+type Database interface {
+	GetValue(ctx context.Context, key []byte) ([]byte, error)
+	Insert(ctx context.Context, key []byte, value []byte) error
+	Remove(ctx context.Context, key []byte) error
+}
@@ -0,0 +1,25 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package chain
+
+import "errors"
+
+var ErrDuplicateTx = errors.New("duplicate transaction")
+
+// HandlePreExecute interprets a PreExecute error into mempool bookkeeping
+// instructions: whether to keep iterating (cont), keep the tx in the
+// mempool for a future attempt (restore), and whether to evict the tx's fee
+// payer from consideration for the rest of this build pass (removeAcct).
+func HandlePreExecute(err error) (cont bool, restore bool, removeAcct bool) {
+	switch {
+	case errors.Is(err, ErrInvalidTimestamp):
+		// Will be valid once time passes; no point retrying this pass.
+		return true, true, false
+	case errors.Is(err, ErrConflictSuperseded):
+		// Permanently invalid; don't keep it around.
+		return true, false, false
+	default:
+		return true, true, false
+	}
+}
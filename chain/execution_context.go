@@ -0,0 +1,82 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package chain
+
+import (
+	"context"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/trace"
+)
+
+// ExecutionContext carries the per-block context Actions may need beyond
+// the raw timestamp (e.g. the chain ID, for domain-separating signatures).
+type ExecutionContext struct {
+	ChainID   ids.ID
+	Timestamp int64
+
+	// landed tracks the txIDs declared as Conflicts by txs that have
+	// already run Execute earlier in this same block, so PreExecute can
+	// reject a tx superseded by a sibling in this block rather than a prior
+	// one (see chain.ConflictChecker, which only sees conflicts persisted by
+	// a completed Execute). This guards the only ordering a builder can use
+	// in this tree — PreExecute, Execute, PreExecute, Execute, ... one tx
+	// at a time against the same mutating block state — not a hypothetical
+	// builder that ran every tx's PreExecute before any tx's Execute.
+	landed map[ids.ID]struct{}
+}
+
+// markLanded records [id] as superseded by a tx that completed Execute in
+// this block.
+func (e *ExecutionContext) markLanded(id ids.ID) {
+	if e.landed == nil {
+		e.landed = make(map[ids.ID]struct{})
+	}
+	e.landed[id] = struct{}{}
+}
+
+// hasLanded reports whether [id] was declared as a conflict by a tx that
+// already completed Execute in this block.
+func (e *ExecutionContext) hasLanded(id ids.ID) bool {
+	_, ok := e.landed[id]
+	return ok
+}
+
+// Block is the minimal view of a preferred/accepted block the gossiper and
+// GenerateExecutionContext need.
+type Block struct {
+	Hght   uint64
+	Tmstmp int64
+
+	state Database
+}
+
+func NewBlock(hght uint64, tmstmp int64, state Database) *Block {
+	return &Block{Hght: hght, Tmstmp: tmstmp, state: state}
+}
+
+func (b *Block) State() (Database, error) { return b.state, nil }
+
+// GenerateExecutionContext builds the ExecutionContext a batch of
+// transactions is built/replayed against.
+func GenerateExecutionContext(
+	ctx context.Context,
+	chainID ids.ID,
+	timestamp int64,
+	blk *Block,
+	tracer trace.Tracer,
+	r Rules,
+) (*ExecutionContext, error) {
+	_, span := tracer.Start(ctx, "GenerateExecutionContext")
+	defer span.End()
+	return &ExecutionContext{ChainID: chainID, Timestamp: timestamp}, nil
+}
+
+// NewExecutionContext builds a minimal ExecutionContext directly, bypassing
+// the need for a live block/tracer. Used by the conformance runner, which
+// replays a vector's Tx against an in-memory Database without standing up a
+// full VM.
+func NewExecutionContext(chainID ids.ID, timestamp int64) *ExecutionContext {
+	return &ExecutionContext{ChainID: chainID, Timestamp: timestamp}
+}
@@ -0,0 +1,83 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package chain
+
+import (
+	"fmt"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/hashing"
+)
+
+// MarshalTxs encodes [txs] as a single AppGossip-sized blob: a tx count
+// followed by each tx's Base, Action, and Auth in turn.
+func MarshalTxs(txs []*Transaction, actionRegistry ActionRegistry, authRegistry AuthRegistry) ([]byte, error) {
+	p := NewWriter()
+	p.PackUint16(uint16(len(txs)))
+	for _, tx := range txs {
+		tx.Base.Marshal(p)
+		p.PackByte(tx.Action.GetTypeID())
+		tx.Action.Marshal(p)
+		p.PackByte(tx.Auth.GetTypeID())
+		tx.Auth.Marshal(p)
+	}
+	if p.Err() != nil {
+		return nil, p.Err()
+	}
+	return p.Bytes(), nil
+}
+
+// UnmarshalTxs decodes a MarshalTxs blob, rejecting a batch larger than
+// [maxTxs].
+func UnmarshalTxs(
+	raw []byte,
+	maxTxs int,
+	actionRegistry ActionRegistry,
+	authRegistry AuthRegistry,
+) ([]*Transaction, error) {
+	p := NewReader(raw)
+	count := int(p.UnpackUint16())
+	if p.Err() != nil {
+		return nil, p.Err()
+	}
+	if count > maxTxs {
+		return nil, fmt.Errorf("tx batch of %d exceeds max of %d", count, maxTxs)
+	}
+
+	txs := make([]*Transaction, count)
+	for i := 0; i < count; i++ {
+		start := p.off
+
+		base, err := UnmarshalBase(p)
+		if err != nil {
+			return nil, err
+		}
+
+		actionTypeID := p.UnpackByte()
+		action, err := actionRegistry.unmarshal(actionTypeID, p)
+		if err != nil {
+			return nil, err
+		}
+
+		authTypeID := p.UnpackByte()
+		auth, err := authRegistry.unmarshal(authTypeID, p)
+		if err != nil {
+			return nil, err
+		}
+
+		if p.Err() != nil {
+			return nil, p.Err()
+		}
+
+		txBytes := raw[start:p.off]
+		txs[i] = &Transaction{
+			Base:   base,
+			Action: action,
+			Auth:   auth,
+			id:     ids.ID(hashing.ComputeHash256Array(txBytes)),
+			bytes:  txBytes,
+		}
+	}
+	return txs, nil
+}
@@ -0,0 +1,133 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package chain
+
+import (
+	"encoding/binary"
+	"errors"
+
+	"github.com/ava-labs/avalanchego/ids"
+)
+
+var ErrShortBuffer = errors.New("buffer too short to unpack")
+
+// Packer is a minimal, allocation-light binary encoder/decoder shared by
+// Base, Action, and Auth (un)marshaling. It mirrors the manual big-endian
+// packing storage.go already uses rather than pulling in a reflection-based
+// codec.
+type Packer struct {
+	b   []byte
+	off int
+	err error
+}
+
+func NewWriter() *Packer         { return &Packer{} }
+func NewReader(b []byte) *Packer { return &Packer{b: b} }
+
+func (p *Packer) Err() error    { return p.err }
+func (p *Packer) Bytes() []byte { return p.b }
+
+func (p *Packer) PackByte(v byte) {
+	if p.err != nil {
+		return
+	}
+	p.b = append(p.b, v)
+}
+
+func (p *Packer) UnpackByte() byte {
+	if p.err != nil {
+		return 0
+	}
+	if p.off+1 > len(p.b) {
+		p.err = ErrShortBuffer
+		return 0
+	}
+	v := p.b[p.off]
+	p.off++
+	return v
+}
+
+func (p *Packer) PackUint16(v uint16) {
+	if p.err != nil {
+		return
+	}
+	p.b = binary.BigEndian.AppendUint16(p.b, v)
+}
+
+func (p *Packer) UnpackUint16() uint16 {
+	if p.err != nil {
+		return 0
+	}
+	if p.off+2 > len(p.b) {
+		p.err = ErrShortBuffer
+		return 0
+	}
+	v := binary.BigEndian.Uint16(p.b[p.off:])
+	p.off += 2
+	return v
+}
+
+func (p *Packer) PackUint64(v uint64) {
+	if p.err != nil {
+		return
+	}
+	p.b = binary.BigEndian.AppendUint64(p.b, v)
+}
+
+func (p *Packer) UnpackUint64() uint64 {
+	if p.err != nil {
+		return 0
+	}
+	if p.off+8 > len(p.b) {
+		p.err = ErrShortBuffer
+		return 0
+	}
+	v := binary.BigEndian.Uint64(p.b[p.off:])
+	p.off += 8
+	return v
+}
+
+func (p *Packer) PackID(id ids.ID) {
+	if p.err != nil {
+		return
+	}
+	p.b = append(p.b, id[:]...)
+}
+
+func (p *Packer) UnpackID() ids.ID {
+	var id ids.ID
+	if p.err != nil {
+		return id
+	}
+	if p.off+len(id) > len(p.b) {
+		p.err = ErrShortBuffer
+		return id
+	}
+	copy(id[:], p.b[p.off:])
+	p.off += len(id)
+	return id
+}
+
+// PackBytes writes a uint16 length prefix followed by [b].
+func (p *Packer) PackBytes(b []byte) {
+	p.PackUint16(uint16(len(b)))
+	if p.err != nil {
+		return
+	}
+	p.b = append(p.b, b...)
+}
+
+func (p *Packer) UnpackBytes() []byte {
+	l := p.UnpackUint16()
+	if p.err != nil {
+		return nil
+	}
+	if p.off+int(l) > len(p.b) {
+		p.err = ErrShortBuffer
+		return nil
+	}
+	v := p.b[p.off : p.off+int(l)]
+	p.off += int(l)
+	return v
+}
@@ -0,0 +1,55 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package chain
+
+import "fmt"
+
+type actionFactory func(p *Packer) (Action, error)
+type authFactory func(p *Packer) (Auth, error)
+
+// ActionRegistry maps a wire type ID to the factory that decodes it, so
+// transactions can round-trip arbitrary VM-specific Action implementations
+// without reflection.
+type ActionRegistry struct {
+	factories map[uint8]actionFactory
+}
+
+func NewActionRegistry() ActionRegistry {
+	return ActionRegistry{factories: map[uint8]actionFactory{}}
+}
+
+// Register associates [typeID] with the given decode function. Registering
+// the same [typeID] twice overwrites the earlier registration.
+func (r ActionRegistry) Register(typeID uint8, f func(p *Packer) (Action, error)) {
+	r.factories[typeID] = f
+}
+
+func (r ActionRegistry) unmarshal(typeID uint8, p *Packer) (Action, error) {
+	f, ok := r.factories[typeID]
+	if !ok {
+		return nil, fmt.Errorf("unknown action type %d", typeID)
+	}
+	return f(p)
+}
+
+// AuthRegistry maps a wire type ID to the factory that decodes it.
+type AuthRegistry struct {
+	factories map[uint8]authFactory
+}
+
+func NewAuthRegistry() AuthRegistry {
+	return AuthRegistry{factories: map[uint8]authFactory{}}
+}
+
+func (r AuthRegistry) Register(typeID uint8, f func(p *Packer) (Auth, error)) {
+	r.factories[typeID] = f
+}
+
+func (r AuthRegistry) unmarshal(typeID uint8, p *Packer) (Auth, error) {
+	f, ok := r.factories[typeID]
+	if !ok {
+		return nil, fmt.Errorf("unknown auth type %d", typeID)
+	}
+	return f(p)
+}
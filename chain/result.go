@@ -0,0 +1,11 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package chain
+
+// Result is the outcome of executing a Transaction's Action.
+type Result struct {
+	Success bool
+	Units   uint64
+	Output  []byte
+}
@@ -0,0 +1,11 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package chain
+
+// Rules parameterizes block-building limits for the rule set active at a
+// given timestamp (see VM.Rules(now)).
+type Rules interface {
+	GetMaxBlockUnits() uint64
+	GetMaxBlockTxs() int
+}
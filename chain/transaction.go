@@ -0,0 +1,97 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package chain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/ava-labs/avalanchego/ids"
+)
+
+var ErrInvalidTimestamp = errors.New("invalid timestamp")
+
+// maxFutureDrift bounds how far past the current block timestamp a tx's
+// Base.Timestamp may sit. Without an upper bound, a tx declaring a
+// Conflicts entry could be dated far enough in the future that the conflict
+// record a VM purges on a fixed TTL (see e.g. tokenvm's conflictTTL)
+// expires before the conflicting tx's own expiry ever arrives, letting it
+// resurface and land after it was believed permanently superseded.
+const maxFutureDrift = 2 * 24 * 60 * 60 // seconds
+
+// Transaction bundles a single Action behind a Base and an Auth.
+type Transaction struct {
+	Base   *Base
+	Action Action
+	Auth   Auth
+
+	id    ids.ID
+	bytes []byte
+}
+
+func (t *Transaction) ID() ids.ID    { return t.id }
+func (t *Transaction) Bytes() []byte { return t.bytes }
+
+func (t *Transaction) MaxUnits(r Rules) (uint64, error) {
+	return t.Action.MaxUnits(r)
+}
+
+// PreExecute runs every check that must hold before Execute is allowed to
+// touch state: the tx isn't expired, its declared Conflicts are
+// structurally valid, and none of them have already landed — whether in a
+// prior block (ConflictChecker, backed by the persisted reverse index) or
+// earlier in this same block (ectx.hasLanded, backed by the conflicts each
+// sibling tx's Execute has already recorded into [ectx]).
+func (t *Transaction) PreExecute(
+	ctx context.Context,
+	ectx *ExecutionContext,
+	r Rules,
+	db Database,
+	timestamp int64,
+) error {
+	if t.Base.Timestamp < timestamp || t.Base.Timestamp > timestamp+maxFutureDrift {
+		return ErrInvalidTimestamp
+	}
+	if err := t.Base.Validate(t.id); err != nil {
+		return err
+	}
+	if ectx.hasLanded(t.id) {
+		return fmt.Errorf("%w: superseded earlier in this block", ErrConflictSuperseded)
+	}
+	if ConflictChecker != nil {
+		landed, supersededBy, err := ConflictChecker(ctx, db, t.id)
+		if err != nil {
+			return err
+		}
+		if landed {
+			return fmt.Errorf("%w: superseded by %s", ErrConflictSuperseded, supersededBy)
+		}
+	}
+	return t.Auth.Verify(ctx, t.bytes)
+}
+
+// Execute applies the Action to [db] and, if a ResultRecorder is wired (see
+// conflict.go), persists the receipt and this tx's declared conflicts.
+func (t *Transaction) Execute(
+	ctx context.Context,
+	ectx *ExecutionContext,
+	r Rules,
+	db Database,
+	timestamp int64,
+) (*Result, error) {
+	result, err := t.Action.Execute(ctx, r, db, timestamp, t.Auth.Actor(), t.id)
+	if err != nil {
+		return nil, err
+	}
+	if ResultRecorder != nil {
+		if err := ResultRecorder(ctx, db, t.id, timestamp, result.Success, result.Units, t.Base.Conflicts); err != nil {
+			return nil, err
+		}
+	}
+	for _, conflictID := range t.Base.Conflicts {
+		ectx.markLanded(conflictID)
+	}
+	return result, nil
+}
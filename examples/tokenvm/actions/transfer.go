@@ -0,0 +1,85 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package actions
+
+import (
+	"context"
+
+	"github.com/ava-labs/avalanchego/ids"
+	smath "github.com/ava-labs/avalanchego/utils/math"
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/crypto"
+	"github.com/ava-labs/hypersdk/examples/tokenvm/storage"
+)
+
+const transferTypeID uint8 = 0x0
+
+// Transfer moves [Value] units of [Asset] from the actor to [To].
+type Transfer struct {
+	To    crypto.PublicKey `json:"to"`
+	Asset ids.ID           `json:"asset"`
+	Value uint64           `json:"value"`
+}
+
+func (*Transfer) GetTypeID() uint8 { return transferTypeID }
+
+func (t *Transfer) Marshal(p *chain.Packer) {
+	p.PackBytes(t.To[:])
+	p.PackID(t.Asset)
+	p.PackUint64(t.Value)
+}
+
+func UnmarshalTransfer(p *chain.Packer) (chain.Action, error) {
+	t := new(Transfer)
+	copy(t.To[:], p.UnpackBytes())
+	t.Asset = p.UnpackID()
+	t.Value = p.UnpackUint64()
+	if p.Err() != nil {
+		return nil, p.Err()
+	}
+	return t, nil
+}
+
+func (*Transfer) MaxUnits(chain.Rules) (uint64, error) {
+	return 1, nil
+}
+
+// Execute moves the balance, reporting a failed (but still billed) receipt
+// rather than an error when the actor's balance is insufficient.
+//
+// The recipient's balance is checked for overflow before the actor's
+// balance is touched: chain.Database has no rollback, so debiting the
+// actor first and only then discovering the credit can't land would burn
+// the actor's funds with nothing credited to anyone.
+func (t *Transfer) Execute(
+	ctx context.Context,
+	_ chain.Rules,
+	db chain.Database,
+	_ int64,
+	actor crypto.PublicKey,
+	_ ids.ID,
+) (*chain.Result, error) {
+	toBal, err := storage.GetBalance(ctx, db, t.To, t.Asset)
+	if err != nil {
+		return &chain.Result{Success: false, Units: 1}, nil
+	}
+	if _, err := smath.Add64(toBal, t.Value); err != nil {
+		return &chain.Result{Success: false, Units: 1}, nil
+	}
+	if err := storage.SubBalance(ctx, db, actor, t.Asset, t.Value); err != nil {
+		return &chain.Result{Success: false, Units: 1}, nil
+	}
+	if err := storage.AddBalance(ctx, db, t.To, t.Asset, t.Value); err != nil {
+		return &chain.Result{Success: false, Units: 1}, nil
+	}
+	return &chain.Result{Success: true, Units: 1}, nil
+}
+
+// Registry returns the ActionRegistry every tokenvm transaction is
+// (un)marshaled against.
+func Registry() chain.ActionRegistry {
+	r := chain.NewActionRegistry()
+	r.Register(transferTypeID, UnmarshalTransfer)
+	return r
+}
@@ -0,0 +1,58 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package auth
+
+import (
+	"context"
+	"errors"
+
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/crypto"
+)
+
+const directTypeID uint8 = 0x0
+
+var ErrInvalidActor = errors.New("invalid actor")
+
+// Direct is a minimal Auth that trusts the embedded public key directly,
+// without a signature. It exists so the conformance corpus and early
+// integration tests can exercise the chain/tx pipeline end to end; a real
+// deployment should register a signature-checking Auth instead.
+type Direct struct {
+	Signer crypto.PublicKey `json:"signer"`
+}
+
+func (*Direct) GetTypeID() uint8 { return directTypeID }
+
+func (d *Direct) Marshal(p *chain.Packer) {
+	p.PackBytes(d.Signer[:])
+}
+
+func UnmarshalDirect(p *chain.Packer) (chain.Auth, error) {
+	d := new(Direct)
+	copy(d.Signer[:], p.UnpackBytes())
+	if p.Err() != nil {
+		return nil, p.Err()
+	}
+	return d, nil
+}
+
+func (*Direct) MaxUnits(chain.Rules) uint64 { return 1 }
+
+func (d *Direct) Verify(_ context.Context, _ []byte) error {
+	if d.Signer == crypto.EmptyPublicKey {
+		return ErrInvalidActor
+	}
+	return nil
+}
+
+func (d *Direct) Actor() crypto.PublicKey { return d.Signer }
+
+// Registry returns the AuthRegistry every tokenvm transaction is
+// (un)marshaled against.
+func Registry() chain.AuthRegistry {
+	r := chain.NewAuthRegistry()
+	r.Register(directTypeID, UnmarshalDirect)
+	return r
+}
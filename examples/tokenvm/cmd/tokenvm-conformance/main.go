@@ -0,0 +1,84 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Command tokenvm-conformance replays a corpus of test vectors against
+// tokenvm's state transition and reports any vector that fails to
+// reproduce its expected post-state or receipt.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/ava-labs/hypersdk/chain/conformance"
+	"github.com/ava-labs/hypersdk/examples/tokenvm/actions"
+	"github.com/ava-labs/hypersdk/examples/tokenvm/auth"
+)
+
+func main() {
+	if err := run(); err != nil {
+		fmt.Fprintf(os.Stderr, "tokenvm-conformance: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run() error {
+	corpus := flag.String("corpus", "", "path to directory of *.json test vectors")
+	skipFlag := flag.String("skip", "", "comma-separated vector IDs to skip")
+	junitOut := flag.String("junit", "", "optional path to write a JUnit XML report")
+	flag.Parse()
+
+	if *corpus == "" {
+		return fmt.Errorf("-corpus is required")
+	}
+
+	skip := map[string]bool{}
+	for _, id := range strings.Split(*skipFlag, ",") {
+		if id == "" {
+			continue
+		}
+		skip[id] = true
+	}
+
+	vectors, err := conformance.LoadCorpus(*corpus, skip)
+	if err != nil {
+		return fmt.Errorf("loading corpus: %w", err)
+	}
+	if len(vectors) == 0 {
+		return fmt.Errorf("no vectors found in %s", *corpus)
+	}
+
+	actionRegistry, authRegistry := actions.Registry(), auth.Registry()
+	outcomes, err := conformance.Run(context.Background(), vectors, actionRegistry, authRegistry)
+	if err != nil {
+		return fmt.Errorf("running corpus: %w", err)
+	}
+
+	if *junitOut != "" {
+		f, err := os.Create(*junitOut)
+		if err != nil {
+			return fmt.Errorf("creating junit report: %w", err)
+		}
+		defer f.Close()
+		if err := conformance.WriteJUnitReport(f, outcomes); err != nil {
+			return fmt.Errorf("writing junit report: %w", err)
+		}
+	}
+
+	failed := 0
+	for _, o := range outcomes {
+		if o.Passed() {
+			continue
+		}
+		failed++
+		fmt.Fprintf(os.Stderr, "FAIL %s: %v\n", o.Vector.ID, o.Err)
+	}
+	fmt.Printf("%d/%d vectors passed\n", len(outcomes)-failed, len(outcomes))
+	if failed > 0 {
+		return fmt.Errorf("%d vector(s) failed", failed)
+	}
+	return nil
+}
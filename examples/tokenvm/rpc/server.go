@@ -0,0 +1,78 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package rpc exposes tokenvm's range-scannable indexes over the VM's
+// standard gorilla/rpc JSON-RPC endpoint.
+package rpc
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/hypersdk/crypto"
+	"github.com/ava-labs/hypersdk/examples/tokenvm/storage"
+)
+
+// JSONRPCServer serves queries backed by a storage.RangeDatabase.
+type JSONRPCServer struct {
+	db storage.RangeDatabase
+}
+
+func NewJSONRPCServer(db storage.RangeDatabase) *JSONRPCServer {
+	return &JSONRPCServer{db: db}
+}
+
+type GetBalancesByOwnerArgs struct {
+	Owner crypto.PublicKey `json:"owner"`
+	Start ids.ID           `json:"start"`
+	Limit int              `json:"limit"`
+}
+
+type GetBalancesByOwnerReply struct {
+	Assets   []ids.ID `json:"assets"`
+	Balances []uint64 `json:"balances"`
+}
+
+// GetBalancesByOwner streams every asset [args.Owner] holds, starting after
+// [args.Start], for portfolio-view queries.
+func (j *JSONRPCServer) GetBalancesByOwner(
+	_ *http.Request,
+	args *GetBalancesByOwnerArgs,
+	reply *GetBalancesByOwnerReply,
+) (err error) {
+	reply.Assets, reply.Balances, err = storage.GetBalancesByOwner(
+		context.Background(), j.db, args.Owner, args.Start, args.Limit,
+	)
+	return err
+}
+
+type GetOrdersByPairArgs struct {
+	In           ids.ID `json:"in"`
+	Out          ids.ID `json:"out"`
+	StartInTick  uint64 `json:"startInTick"`
+	StartOutTick uint64 `json:"startOutTick"`
+	StartTxID    ids.ID `json:"startTxID"`
+	Limit        int    `json:"limit"`
+}
+
+type GetOrdersByPairReply struct {
+	Orders []*storage.Order `json:"orders"`
+}
+
+// GetOrdersByPair streams open orders for the (in, out) pair, cheapest
+// first, for order-book depth queries. Each returned storage.Order carries
+// its InTick/OutTick, so a caller can page to the next batch by passing
+// the last order's InTick/OutTick/ID back in as
+// StartInTick/StartOutTick/StartTxID without an extra GetOrder round-trip.
+func (j *JSONRPCServer) GetOrdersByPair(
+	_ *http.Request,
+	args *GetOrdersByPairArgs,
+	reply *GetOrdersByPairReply,
+) (err error) {
+	reply.Orders, err = storage.GetOrdersByPair(
+		context.Background(), j.db, args.In, args.Out,
+		args.StartInTick, args.StartOutTick, args.StartTxID, args.Limit,
+	)
+	return err
+}
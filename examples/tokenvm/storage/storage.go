@@ -8,6 +8,7 @@ import (
 	"encoding/binary"
 	"errors"
 	"fmt"
+	"math/bits"
 
 	"github.com/ava-labs/avalanchego/database"
 	"github.com/ava-labs/avalanchego/ids"
@@ -21,6 +22,18 @@ import (
 
 type ReadState func(context.Context, [][]byte) ([][]byte, []error)
 
+// RangeDatabase is implemented by any chain.Database that can also stream
+// key/value pairs over a prefix range, ordered by key. [start] is excluded
+// from the results (pass the prefix itself to start from the beginning),
+// so paging by passing back the last-seen key as [start] never re-returns
+// it, and at most [limit] pairs are returned, letting callers page through
+// large indexes like the order book or a portfolio view without an
+// off-chain indexer.
+type RangeDatabase interface {
+	chain.Database
+	IterateRange(ctx context.Context, prefix []byte, start []byte, limit int) (keys [][]byte, values [][]byte, err error)
+}
+
 // Metadata
 // 0x0/ (tx)
 //   -> [txID] => timestamp
@@ -34,14 +47,38 @@ type ReadState func(context.Context, [][]byte) ([][]byte, []error)
 //   -> [txID] => in|out|rate|remaining|owner
 // 0x3/ (warpMessages)
 //   -> [messageID]
+// 0x4/ (conflicts)
+//   -> [conflictTxID] => declaringTxID
+// 0x5/ (balanceByOwner)
+//   -> [owner|asset] => (nothing, index only)
+// 0x6/ (ordersByPair)
+//   -> [in|out|priceBE|txID] => (nothing, index only)
+// 0x7/ (lastPurgeSweep)
+//   -> (nothing, single key) => timestamp
 
 const (
 	txPrefix = 0x0
 
-	balancePrefix     = 0x0
-	assetPrefix       = 0x1
-	orderPrefix       = 0x2
-	warpMessagePrefix = 0x3
+	balancePrefix        = 0x0
+	assetPrefix          = 0x1
+	orderPrefix          = 0x2
+	warpMessagePrefix    = 0x3
+	conflictPrefix       = 0x4
+	balanceByOwnerPrefix = 0x5
+	ordersByPairPrefix   = 0x6
+	lastPurgeSweepPrefix = 0x7
+
+	// conflictTTL is how long, in seconds, a conflict record remains able to
+	// reject its target before it is eligible for purging.
+	conflictTTL = 2 * 24 * 60 * 60 // 2 days
+	// conflictPurgeBatch bounds how many expired conflict records are purged
+	// per opportunistic sweep (see PurgeExpiredConflicts).
+	conflictPurgeBatch = 64
+
+	// priceScale fixed-points an order's true rate (inTick/outTick) so two
+	// orders expressing the same price with different tick sizes sort
+	// identically (see PrefixOrdersByPairKey).
+	priceScale = 1_000_000_000
 )
 
 var (
@@ -65,6 +102,7 @@ func StoreTransaction(
 	t int64,
 	success bool,
 	units uint64,
+	conflicts []ids.ID,
 ) error {
 	k := PrefixTxKey(id)
 	v := make([]byte, consts.Uint64Len+1+consts.Uint64Len)
@@ -75,7 +113,18 @@ func StoreTransaction(
 		v[consts.Uint64Len] = failureByte
 	}
 	binary.BigEndian.PutUint64(v[consts.Uint64Len+1:], units)
-	return db.Put(k, v)
+	if err := db.Put(k, v); err != nil {
+		return err
+	}
+	// Index each declared conflict so a later attempt to land [conflictID]
+	// can be rejected by [HasConflict] in PreExecute, even if [conflictID] is
+	// attempted in a later block than the one [id] landed in.
+	for _, conflictID := range conflicts {
+		if err := db.Put(PrefixConflictKey(conflictID), id[:]); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 func GetTransaction(
@@ -109,6 +158,53 @@ func PrefixBalanceKey(pk crypto.PublicKey, asset ids.ID) (k []byte) {
 	return
 }
 
+// [balanceByOwnerPrefix] + [owner] + [asset]
+//
+// Mirrors PrefixBalanceKey under a dedicated prefix so GetBalancesByOwner
+// can range over every asset held by [pk] with a single prefix scan.
+func PrefixBalanceByOwnerKey(pk crypto.PublicKey, asset ids.ID) (k []byte) {
+	k = make([]byte, 1+crypto.PublicKeyLen+consts.IDLen)
+	k[0] = balanceByOwnerPrefix
+	copy(k[1:], pk[:])
+	copy(k[1+crypto.PublicKeyLen:], asset[:])
+	return
+}
+
+// GetBalancesByOwner streams the balances held by [pk], starting after
+// [start] (use ids.Empty to start from the beginning) and returning at
+// most [limit] assets. It is the range-scannable counterpart to
+// GetBalance, used to serve portfolio-view RPC queries.
+func GetBalancesByOwner(
+	ctx context.Context,
+	db RangeDatabase,
+	pk crypto.PublicKey,
+	start ids.ID,
+	limit int,
+) ([]ids.ID, []uint64, error) {
+	prefix := make([]byte, 1+crypto.PublicKeyLen)
+	prefix[0] = balanceByOwnerPrefix
+	copy(prefix[1:], pk[:])
+
+	keys, _, err := db.IterateRange(ctx, prefix, PrefixBalanceByOwnerKey(pk, start), limit)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	assets := make([]ids.ID, 0, len(keys))
+	balances := make([]uint64, 0, len(keys))
+	for _, k := range keys {
+		var asset ids.ID
+		copy(asset[:], k[1+crypto.PublicKeyLen:])
+		balance, err := GetBalance(ctx, db, pk, asset)
+		if err != nil {
+			return nil, nil, err
+		}
+		assets = append(assets, asset)
+		balances = append(balances, balance)
+	}
+	return assets, balances, nil
+}
+
 // If locked is 0, then account does not exist
 func GetBalance(
 	ctx context.Context,
@@ -153,7 +249,10 @@ func SetBalance(
 ) error {
 	k := PrefixBalanceKey(pk, asset)
 	b := binary.BigEndian.AppendUint64(nil, balance)
-	return db.Insert(ctx, k, b)
+	if err := db.Insert(ctx, k, b); err != nil {
+		return err
+	}
+	return db.Insert(ctx, PrefixBalanceByOwnerKey(pk, asset), nil)
 }
 
 func DeleteBalance(
@@ -162,7 +261,10 @@ func DeleteBalance(
 	pk crypto.PublicKey,
 	asset ids.ID,
 ) error {
-	return db.Remove(ctx, PrefixBalanceKey(pk, asset))
+	if err := db.Remove(ctx, PrefixBalanceKey(pk, asset)); err != nil {
+		return err
+	}
+	return db.Remove(ctx, PrefixBalanceByOwnerKey(pk, asset))
 }
 
 func AddBalance(
@@ -215,7 +317,7 @@ func SubBalance(
 	if nbal == 0 {
 		// If there is no balance left, we should delete the record instead of
 		// setting it to 0.
-		return db.Remove(ctx, PrefixBalanceKey(pk, asset))
+		return DeleteBalance(ctx, db, pk, asset)
 	}
 	return SetBalance(ctx, db, pk, asset, nbal)
 }
@@ -310,7 +412,10 @@ func SetOrder(
 	binary.BigEndian.PutUint64(v[consts.IDLen*2+consts.Uint64Len:], outTick)
 	binary.BigEndian.PutUint64(v[consts.IDLen*2+consts.Uint64Len*2:], supply)
 	copy(v[consts.IDLen*2+consts.Uint64Len*3:], owner[:])
-	return db.Insert(ctx, k, v)
+	if err := db.Insert(ctx, k, v); err != nil {
+		return err
+	}
+	return db.Insert(ctx, PrefixOrdersByPairKey(in, out, inTick, outTick, txID), nil)
 }
 
 func GetOrder(
@@ -348,10 +453,116 @@ func GetOrder(
 }
 
 func DeleteOrder(ctx context.Context, db chain.Database, order ids.ID) error {
+	exists, in, inTick, out, outTick, _, _, err := GetOrder(ctx, db, order)
+	if err != nil {
+		return err
+	}
+	if exists {
+		if err := db.Remove(ctx, PrefixOrdersByPairKey(in, out, inTick, outTick, order)); err != nil {
+			return err
+		}
+	}
 	k := PrefixOrderKey(order)
 	return db.Remove(ctx, k)
 }
 
+// rate fixed-points the true price of an order (inTick/outTick) to a
+// sortable uint64, scaled by [priceScale]. Two orders with the same true
+// price always encode to the same value regardless of the tick sizes used
+// to express it, which a raw outTick cannot guarantee.
+func rate(inTick, outTick uint64) uint64 {
+	if outTick == 0 {
+		return 0
+	}
+	hi, lo := bits.Mul64(inTick, priceScale)
+	if hi >= outTick {
+		// Overflows the fixed-point range: sort it last rather than
+		// panicking in bits.Div64.
+		return ^uint64(0)
+	}
+	q, _ := bits.Div64(hi, lo, outTick)
+	return q
+}
+
+// [ordersByPairPrefix] + [in] + [out] + [rateBE] + [txID]
+//
+// [rateBE] is the big-endian, fixed-point encoding of inTick/outTick (see
+// rate), so that a prefix scan over [in|out] yields orders sorted from
+// cheapest to most expensive regardless of the tick sizes used to express
+// each order's price, matching the shape of an order-book depth query.
+func PrefixOrdersByPairKey(in ids.ID, out ids.ID, inTick uint64, outTick uint64, txID ids.ID) (k []byte) {
+	k = make([]byte, 1+consts.IDLen*2+consts.Uint64Len+consts.IDLen)
+	k[0] = ordersByPairPrefix
+	copy(k[1:], in[:])
+	copy(k[1+consts.IDLen:], out[:])
+	binary.BigEndian.PutUint64(k[1+consts.IDLen*2:], rate(inTick, outTick))
+	copy(k[1+consts.IDLen*2+consts.Uint64Len:], txID[:])
+	return
+}
+
+// Order is a single row of an order-book depth query: enough to place the
+// order in the book and, via InTick/OutTick, to seed the next
+// GetOrdersByPair page as StartInTick/StartOutTick/StartTxID without an
+// extra GetOrder round-trip per result.
+type Order struct {
+	ID        ids.ID           `json:"id"`
+	InTick    uint64           `json:"inTick"`
+	OutTick   uint64           `json:"outTick"`
+	Remaining uint64           `json:"remaining"`
+	Owner     crypto.PublicKey `json:"owner"`
+}
+
+// GetOrdersByPair streams open orders for the (in, out) asset pair,
+// cheapest first, starting after [startInTick]/[startOutTick]/[startTxID]
+// (use 0/0/ids.Empty to start from the beginning) and returning at most
+// [limit] orders. It is the range-scannable counterpart to GetOrder, used
+// to serve order-book depth RPC queries without an off-chain indexer.
+func GetOrdersByPair(
+	ctx context.Context,
+	db RangeDatabase,
+	in ids.ID,
+	out ids.ID,
+	startInTick uint64,
+	startOutTick uint64,
+	startTxID ids.ID,
+	limit int,
+) ([]*Order, error) {
+	prefix := make([]byte, 1+consts.IDLen*2)
+	prefix[0] = ordersByPairPrefix
+	copy(prefix[1:], in[:])
+	copy(prefix[1+consts.IDLen:], out[:])
+
+	keys, _, err := db.IterateRange(
+		ctx, prefix, PrefixOrdersByPairKey(in, out, startInTick, startOutTick, startTxID), limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	orders := make([]*Order, 0, len(keys))
+	for _, k := range keys {
+		var txID ids.ID
+		copy(txID[:], k[1+consts.IDLen*2+consts.Uint64Len:])
+		exists, _, inTick, _, outTick, remaining, owner, err := GetOrder(ctx, db, txID)
+		if err != nil {
+			return nil, err
+		}
+		if !exists {
+			// The orders-by-pair index entry outlived the order row itself
+			// (e.g. a purge race); skip rather than return a hollow row.
+			continue
+		}
+		orders = append(orders, &Order{
+			ID:        txID,
+			InTick:    inTick,
+			OutTick:   outTick,
+			Remaining: remaining,
+			Owner:     owner,
+		})
+	}
+	return orders, nil
+}
+
 func PrefixWarpMessageKey(txID ids.ID) []byte {
 	k := make([]byte, 1+consts.IDLen)
 	k[0] = warpMessagePrefix
@@ -375,3 +586,109 @@ func HasWarpMessageID(ctx context.Context, db chain.Database, txID ids.ID) (bool
 	}
 	return true, nil
 }
+
+// [conflictPrefix] + [txID]
+func PrefixConflictKey(txID ids.ID) (k []byte) {
+	k = make([]byte, 1+consts.IDLen)
+	k[0] = conflictPrefix
+	copy(k[1:], txID[:])
+	return
+}
+
+// HasConflict returns whether [id] was declared as a conflict by a
+// transaction that has already landed, and if so, the ID of the
+// transaction that superseded it.
+func HasConflict(
+	ctx context.Context,
+	db chain.Database,
+	id ids.ID,
+) (bool, ids.ID, error) {
+	k := PrefixConflictKey(id)
+	v, err := db.GetValue(ctx, k)
+	if errors.Is(err, database.ErrNotFound) {
+		return false, ids.Empty, nil
+	}
+	if err != nil {
+		return false, ids.Empty, err
+	}
+	var supersededBy ids.ID
+	copy(supersededBy[:], v)
+	return true, supersededBy, nil
+}
+
+// DeleteConflict purges a conflict record once the declaring transaction's
+// TTL has expired and it can no longer be used to reject [id].
+func DeleteConflict(ctx context.Context, db chain.Database, id ids.ID) error {
+	return db.Remove(ctx, PrefixConflictKey(id))
+}
+
+// PurgeExpiredConflicts removes up to [limit] conflict records whose
+// declaring transaction is older than [ttl] relative to [now], so the
+// reverse index doesn't grow unbounded. It is called opportunistically from
+// recordResult (see wire.go), gated on elapsed time since the last sweep
+// rather than running on every landed tx, instead of from a separate timer.
+func PurgeExpiredConflicts(
+	ctx context.Context,
+	db RangeDatabase,
+	now int64,
+	ttl int64,
+	limit int,
+) (int, error) {
+	prefix := []byte{conflictPrefix}
+	keys, values, err := db.IterateRange(ctx, prefix, prefix, limit)
+	if err != nil {
+		return 0, err
+	}
+
+	purged := 0
+	for i, k := range keys {
+		var declaringTxID ids.ID
+		copy(declaringTxID[:], values[i])
+
+		v, err := db.GetValue(ctx, PrefixTxKey(declaringTxID))
+		if errors.Is(err, database.ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return purged, err
+		}
+		landedAt := int64(binary.BigEndian.Uint64(v))
+		if now-landedAt < ttl {
+			continue
+		}
+
+		var conflictID ids.ID
+		copy(conflictID[:], k[1:])
+		if err := DeleteConflict(ctx, db, conflictID); err != nil {
+			return purged, err
+		}
+		purged++
+	}
+	return purged, nil
+}
+
+// PrefixLastPurgeSweepKey is the single key recordResult (see wire.go) reads
+// and updates to throttle how often it calls PurgeExpiredConflicts.
+func PrefixLastPurgeSweepKey() (k []byte) {
+	return []byte{lastPurgeSweepPrefix}
+}
+
+// GetLastPurgeSweep returns the timestamp PurgeExpiredConflicts last ran at,
+// or 0 if it has never run.
+func GetLastPurgeSweep(ctx context.Context, db chain.Database) (int64, error) {
+	v, err := db.GetValue(ctx, PrefixLastPurgeSweepKey())
+	if errors.Is(err, database.ErrNotFound) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	return int64(binary.BigEndian.Uint64(v)), nil
+}
+
+// SetLastPurgeSweep records [timestamp] as the last time PurgeExpiredConflicts ran.
+func SetLastPurgeSweep(ctx context.Context, db chain.Database, timestamp int64) error {
+	v := make([]byte, consts.Uint64Len)
+	binary.BigEndian.PutUint64(v, uint64(timestamp))
+	return db.Insert(ctx, PrefixLastPurgeSweepKey(), v)
+}
@@ -0,0 +1,211 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package storage
+
+import (
+	"bytes"
+	"context"
+	"sort"
+	"testing"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/hypersdk/crypto"
+	"github.com/stretchr/testify/require"
+)
+
+// testDB is a minimal in-memory RangeDatabase, mirroring
+// chain/conformance's memDB: [start] is excluded from IterateRange
+// results, matching the "starting after [start]" contract every range
+// query in this package documents.
+type testDB struct {
+	kv map[string][]byte
+}
+
+func newTestDB() *testDB {
+	return &testDB{kv: make(map[string][]byte)}
+}
+
+func (db *testDB) GetValue(_ context.Context, key []byte) ([]byte, error) {
+	v, ok := db.kv[string(key)]
+	if !ok {
+		return nil, database.ErrNotFound
+	}
+	return v, nil
+}
+
+func (db *testDB) Insert(_ context.Context, key []byte, value []byte) error {
+	db.kv[string(key)] = value
+	return nil
+}
+
+func (db *testDB) Remove(_ context.Context, key []byte) error {
+	delete(db.kv, string(key))
+	return nil
+}
+
+// Get/Put/Has/Delete satisfy avalanchego's database.KeyValueReader/Writer,
+// the interface StoreTransaction expects (see wire.go's recordResult).
+func (db *testDB) Get(key []byte) ([]byte, error) {
+	return db.GetValue(context.Background(), key)
+}
+
+func (db *testDB) Put(key []byte, value []byte) error {
+	return db.Insert(context.Background(), key, value)
+}
+
+func (db *testDB) Has(key []byte) (bool, error) {
+	_, ok := db.kv[string(key)]
+	return ok, nil
+}
+
+func (db *testDB) Delete(key []byte) error {
+	return db.Remove(context.Background(), key)
+}
+
+func (db *testDB) IterateRange(
+	_ context.Context,
+	prefix []byte,
+	start []byte,
+	limit int,
+) ([][]byte, [][]byte, error) {
+	type kv struct {
+		k, v []byte
+	}
+	all := make([]kv, 0, len(db.kv))
+	for k, v := range db.kv {
+		all = append(all, kv{k: []byte(k), v: v})
+	}
+	sort.Slice(all, func(i, j int) bool { return bytes.Compare(all[i].k, all[j].k) < 0 })
+
+	var keys, values [][]byte
+	for _, e := range all {
+		if !bytes.HasPrefix(e.k, prefix) {
+			continue
+		}
+		if bytes.Compare(e.k, start) <= 0 {
+			continue
+		}
+		keys = append(keys, e.k)
+		values = append(values, e.v)
+		if limit > 0 && len(keys) >= limit {
+			break
+		}
+	}
+	return keys, values, nil
+}
+
+func TestRate(t *testing.T) {
+	tests := []struct {
+		name    string
+		inTick  uint64
+		outTick uint64
+		want    uint64
+	}{
+		{name: "zero outTick returns zero", inTick: 5, outTick: 0, want: 0},
+		{name: "equal ticks is unit price", inTick: 10, outTick: 10, want: priceScale},
+		{name: "half price", inTick: 1, outTick: 2, want: priceScale / 2},
+		{name: "overflow sorts last", inTick: ^uint64(0), outTick: 1, want: ^uint64(0)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, rate(tt.inTick, tt.outTick))
+		})
+	}
+}
+
+// TestRateSamePriceDifferentTicksSortsEqual guards the property
+// PrefixOrdersByPairKey depends on: two orders expressing the same true
+// price with different tick sizes must encode to the same rate.
+func TestRateSamePriceDifferentTicksSortsEqual(t *testing.T) {
+	require.Equal(t, rate(1, 2), rate(5, 10))
+	require.Less(t, rate(1, 3), rate(1, 2))
+}
+
+func TestHasConflict(t *testing.T) {
+	db := newTestDB()
+	ctx := context.Background()
+
+	declaring := ids.GenerateTestID()
+	conflict := ids.GenerateTestID()
+
+	landed, _, err := HasConflict(ctx, db, conflict)
+	require.NoError(t, err)
+	require.False(t, landed)
+
+	require.NoError(t, StoreTransaction(ctx, db, declaring, 100, true, 1, []ids.ID{conflict}))
+
+	landed, supersededBy, err := HasConflict(ctx, db, conflict)
+	require.NoError(t, err)
+	require.True(t, landed)
+	require.Equal(t, declaring, supersededBy)
+}
+
+func TestPurgeExpiredConflicts(t *testing.T) {
+	db := newTestDB()
+	ctx := context.Background()
+
+	const ttl = int64(100)
+
+	expired := ids.GenerateTestID()
+	expiredConflict := ids.GenerateTestID()
+	require.NoError(t, StoreTransaction(ctx, db, expired, 0, true, 1, []ids.ID{expiredConflict}))
+
+	fresh := ids.GenerateTestID()
+	freshConflict := ids.GenerateTestID()
+	require.NoError(t, StoreTransaction(ctx, db, fresh, 50, true, 1, []ids.ID{freshConflict}))
+
+	purged, err := PurgeExpiredConflicts(ctx, db, 100, ttl, 64)
+	require.NoError(t, err)
+	require.Equal(t, 1, purged)
+
+	landed, _, err := HasConflict(ctx, db, expiredConflict)
+	require.NoError(t, err)
+	require.False(t, landed)
+
+	landed, _, err = HasConflict(ctx, db, freshConflict)
+	require.NoError(t, err)
+	require.True(t, landed)
+}
+
+func TestGetOrdersByPairPagination(t *testing.T) {
+	db := newTestDB()
+	ctx := context.Background()
+
+	in := ids.GenerateTestID()
+	out := ids.GenerateTestID()
+
+	const numOrders = 5
+	for i := uint64(0); i < numOrders; i++ {
+		txID := ids.GenerateTestID()
+		// inTick fixed, outTick descending so price (inTick/outTick)
+		// increases with i, giving each order a distinct rate.
+		require.NoError(t, SetOrder(
+			ctx, db, txID, in, 1, out, numOrders-i, 1, crypto.EmptyPublicKey,
+		))
+	}
+
+	var seen []ids.ID
+	startInTick, startOutTick, startTxID := uint64(0), uint64(0), ids.Empty
+	for {
+		page, err := GetOrdersByPair(ctx, db, in, out, startInTick, startOutTick, startTxID, 2)
+		require.NoError(t, err)
+		if len(page) == 0 {
+			break
+		}
+		for _, o := range page {
+			seen = append(seen, o.ID)
+		}
+		last := page[len(page)-1]
+		startInTick, startOutTick, startTxID = last.InTick, last.OutTick, last.ID
+	}
+
+	require.Len(t, seen, numOrders)
+	unique := make(map[ids.ID]struct{}, len(seen))
+	for _, id := range seen {
+		_, dup := unique[id]
+		require.False(t, dup, "page boundary returned %s twice", id)
+		unique[id] = struct{}{}
+	}
+}
@@ -0,0 +1,69 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package storage
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/hypersdk/chain"
+)
+
+// init wires the generic chain.Transaction pipeline into tokenvm's concrete
+// storage layout: chain itself is VM-agnostic and never reaches into a
+// specific prefix scheme directly (see chain/conflict.go).
+func init() {
+	chain.ConflictChecker = HasConflict
+	chain.ResultRecorder = recordResult
+}
+
+// conflictPurgeSampleWindow throttles PurgeExpiredConflicts to roughly once
+// per window of block time rather than on every single landed tx: the sweep
+// is a range scan plus up to conflictPurgeBatch point lookups, and running
+// it on every tx would add avoidable I/O to the hot execution path for no
+// benefit once the conflict index isn't actually growing stale entries.
+//
+// Gated on elapsed time since the last sweep (GetLastPurgeSweep), not on
+// timestamp%conflictPurgeSampleWindow: block timestamps essentially never
+// land on an exact multiple of the window, so a modulo check would make the
+// sweep run effectively never instead of roughly every window.
+const conflictPurgeSampleWindow = 3600 // seconds
+
+// recordResult persists a landed tx's receipt and declared conflicts, and
+// opportunistically sweeps a few expired conflict records while it has the
+// database open.
+func recordResult(
+	ctx context.Context,
+	db chain.Database,
+	txID ids.ID,
+	timestamp int64,
+	success bool,
+	units uint64,
+	conflicts []ids.ID,
+) error {
+	kv, ok := db.(database.KeyValueWriter)
+	if !ok {
+		return fmt.Errorf("database %T does not support tx receipts", db)
+	}
+	if err := StoreTransaction(ctx, kv, txID, timestamp, success, units, conflicts); err != nil {
+		return err
+	}
+	if rdb, ok := db.(RangeDatabase); ok {
+		lastSweep, err := GetLastPurgeSweep(ctx, db)
+		if err != nil {
+			return err
+		}
+		if timestamp-lastSweep >= conflictPurgeSampleWindow {
+			if _, err := PurgeExpiredConflicts(ctx, rdb, timestamp, conflictTTL, conflictPurgeBatch); err != nil {
+				return err
+			}
+			if err := SetLastPurgeSweep(ctx, db, timestamp); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -6,6 +6,7 @@ package gossiper
 import (
 	"context"
 	"errors"
+	"sync"
 	"time"
 
 	"github.com/ava-labs/avalanchego/cache"
@@ -14,13 +15,24 @@ import (
 	"github.com/ava-labs/avalanchego/utils/set"
 	"github.com/ava-labs/avalanchego/vms/proposervm/proposer"
 	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/consts"
 	"go.uber.org/zap"
 )
 
+// AppGossip message types. The first byte of every gossip payload is one
+// of these, followed by a type-specific body.
+const (
+	msgTxs    byte = 0 // body is a chain.MarshalTxs blob of full tx bodies
+	msgDigest byte = 1 // body is a list of tx IDs a peer already has
+	msgWant   byte = 2 // body is a list of tx IDs a peer is missing
+)
+
 var _ Gossiper = (*Proposer)(nil)
 
 var proposerWindow = int64(proposer.MaxDelay.Seconds())
 
+var errInvalidDigest = errors.New("gossip digest is not a multiple of the ID length")
+
 type Proposer struct {
 	vm        VM
 	cfg       *ProposerConfig
@@ -30,7 +42,34 @@ type Proposer struct {
 
 	// bounded by validator count (may be slightly out of date as composition changes)
 	gossipedTxs map[ids.NodeID]*cache.LRU[ids.ID, struct{}]
-	receivedTxs *cache.LRU[ids.ID, struct{}]
+	// receivedTxs maps a tx ID to when we first saw it, so a later
+	// duplicate delivery can be scored by how stale it was.
+	receivedTxs *cache.LRU[ids.ID, time.Time]
+
+	// conflictedTxs tracks tx IDs that a gossiped or mempool-bound tx has
+	// declared as a conflict. A tx that appears here has been superseded
+	// and should be dropped rather than gossiped or admitted again.
+	conflictedTxs *cache.LRU[ids.ID, struct{}]
+
+	// txCache holds the bodies of txs we've recently built or received, so
+	// IHAVE/IWANT handling can serve a peer's request without depending on
+	// a Mempool.Get method the VM surface handed to this package doesn't
+	// expose.
+	txCache *cache.LRU[ids.ID, *chain.Transaction]
+
+	// mesh is the scored GossipSub-style peer set we actively gossip to,
+	// grafted from the validator set and pruned by score. Guarded by
+	// meshMu because it is read/written from both the gossip loop (Run)
+	// and the network thread (HandleAppGossip).
+	meshMu sync.Mutex
+	mesh   map[ids.NodeID]*PeerScore
+
+	// backoff holds the time a peer pruned for Score<0 becomes eligible to
+	// be grafted back into the mesh. Without it, graftMesh would re-add a
+	// just-pruned peer with a fresh PeerScore{Score:0} on the very next
+	// call in the same Run tick, making the prune a no-op. Guarded by
+	// meshMu, same as mesh.
+	backoff map[ids.NodeID]time.Time
 }
 
 type ProposerConfig struct {
@@ -39,8 +78,41 @@ type ProposerConfig struct {
 	GossipInterval          time.Duration
 	GossipPeerCacheSize     int
 	GossipReceivedCacheSize int
+	GossipConflictCacheSize int
+	GossipTxCacheSize       int
 	GossipMinLife           int64 // seconds
 	BuildProposerDiff       int
+
+	// GossipMeshTargetDegree ("D") is the number of peers we try to keep
+	// in the mesh; GossipMeshMaxDegree ("D_hi") is the point past which
+	// we start pruning the lowest-scored peers.
+	GossipMeshTargetDegree int
+	GossipMeshMaxDegree    int
+
+	// GossipDigestThreshold is the mesh degree past which we switch from
+	// sending full tx bodies to sending IHAVE-style ID digests, letting
+	// peers IWANT only what they're missing. Must be set below
+	// GossipMeshTargetDegree: graftMesh never grows the mesh past
+	// GossipMeshTargetDegree, so a threshold at or above it means the
+	// digest path never triggers and every send marshals full tx bodies.
+	GossipDigestThreshold int
+
+	// Scoring recurrence (see PeerScore): delivered-first credit (P1),
+	// duplicate-delivery credit (P2) decayed linearly over
+	// GossipScoreDuplicateWindow, unmarshal penalty (P3), invalid-tx
+	// penalty (P4), and the per-GossipInterval decay factor (d).
+	GossipScoreDeliveredFirst   float64
+	GossipScoreDuplicateCredit  float64
+	GossipScoreDuplicateWindow  time.Duration
+	GossipScoreUnmarshalPenalty float64
+	GossipScoreInvalidTxPenalty float64
+	GossipScoreDecay            float64
+
+	// GossipScoreBackoff is how long a peer pruned from the mesh for
+	// Score<0 is withheld from graftMesh, so a penalty survives past the
+	// same-tick prune-then-regraft cycle instead of being reset to
+	// PeerScore{Score:0} immediately.
+	GossipScoreBackoff time.Duration
 }
 
 func DefaultProposerConfig() *ProposerConfig {
@@ -50,8 +122,22 @@ func DefaultProposerConfig() *ProposerConfig {
 		GossipInterval:          1 * time.Second,
 		GossipPeerCacheSize:     10_240,
 		GossipReceivedCacheSize: 65_536,
+		GossipConflictCacheSize: 65_536,
+		GossipTxCacheSize:       65_536,
 		GossipMinLife:           5,
 		BuildProposerDiff:       2,
+
+		GossipMeshTargetDegree: 6,
+		GossipMeshMaxDegree:    8,
+		GossipDigestThreshold:  4,
+
+		GossipScoreDeliveredFirst:   1,
+		GossipScoreDuplicateCredit:  0.5,
+		GossipScoreDuplicateWindow:  2 * time.Second,
+		GossipScoreUnmarshalPenalty: 5,
+		GossipScoreInvalidTxPenalty: 2,
+		GossipScoreDecay:            0.9,
+		GossipScoreBackoff:          30 * time.Second,
 	}
 }
 
@@ -62,8 +148,21 @@ func NewProposer(vm VM, cfg *ProposerConfig) *Proposer {
 
 		doneGossip: make(chan struct{}),
 
-		gossipedTxs: map[ids.NodeID]*cache.LRU[ids.ID, struct{}]{},
-		receivedTxs: &cache.LRU[ids.ID, struct{}]{Size: cfg.GossipReceivedCacheSize},
+		gossipedTxs:   map[ids.NodeID]*cache.LRU[ids.ID, struct{}]{},
+		receivedTxs:   &cache.LRU[ids.ID, time.Time]{Size: cfg.GossipReceivedCacheSize},
+		conflictedTxs: &cache.LRU[ids.ID, struct{}]{Size: cfg.GossipConflictCacheSize},
+		txCache:       &cache.LRU[ids.ID, *chain.Transaction]{Size: cfg.GossipTxCacheSize},
+		mesh:          map[ids.NodeID]*PeerScore{},
+		backoff:       map[ids.NodeID]time.Time{},
+	}
+}
+
+// markConflicts records every tx ID that [tx] declares as a conflict so
+// later gossip/build passes can drop the superseded tx instead of wasting
+// bandwidth or mempool space on it.
+func (g *Proposer) markConflicts(tx *chain.Transaction) {
+	for _, conflictID := range tx.Base.Conflicts {
+		g.conflictedTxs.Put(conflictID, struct{}{})
 	}
 }
 
@@ -71,22 +170,19 @@ func (g *Proposer) sendTxs(ctx context.Context, txs []*chain.Transaction) error
 	ctx, span := g.vm.Tracer().Start(ctx, "Gossiper.sendTxs")
 	defer span.End()
 
-	proposers, err := g.vm.Proposers(
-		ctx,
-		g.cfg.GossipProposerDiff,
-		g.cfg.GossipProposerDepth,
-	)
-	if err != nil || proposers.Len() == 0 {
-		g.vm.Logger().Warn(
-			"unable to find any proposers, falling back to all-to-all gossip",
-			zap.Error(err),
-		)
+	// Keep the mesh topped up before every send so a cold start (or a
+	// mesh drained by pruning) still reaches peers.
+	g.graftMesh(ctx)
+	peers := g.meshPeers()
+	if len(peers) == 0 {
+		g.vm.Logger().Warn("gossip mesh is empty, falling back to all-to-all gossip")
 
 		actionRegistry, authRegistry := g.vm.Registry()
 		b, err := chain.MarshalTxs(txs, actionRegistry, authRegistry)
 		if err != nil {
 			return err
 		}
+		b = append([]byte{msgTxs}, b...)
 
 		if err := g.appSender.SendAppGossip(ctx, b); err != nil {
 			g.vm.Logger().Warn(
@@ -98,16 +194,21 @@ func (g *Proposer) sendTxs(ctx context.Context, txs []*chain.Transaction) error
 		return nil
 	}
 
-	for proposer := range proposers {
+	// Past [GossipDigestThreshold] peers, it's cheaper to advertise tx IDs
+	// and let each peer IWANT only what it's missing than to marshal and
+	// send full bodies to every mesh member.
+	useDigest := len(peers) > g.cfg.GossipDigestThreshold
+
+	for _, peer := range peers {
 		// Don't gossip to self
-		if proposer == g.vm.NodeID() {
+		if peer == g.vm.NodeID() {
 			continue
 		}
 
-		c, ok := g.gossipedTxs[proposer]
+		c, ok := g.gossipedTxs[peer]
 		if !ok {
-			g.gossipedTxs[proposer] = &cache.LRU[ids.ID, struct{}]{Size: g.cfg.GossipPeerCacheSize}
-			c = g.gossipedTxs[proposer]
+			g.gossipedTxs[peer] = &cache.LRU[ids.ID, struct{}]{Size: g.cfg.GossipPeerCacheSize}
+			c = g.gossipedTxs[peer]
 		}
 
 		toGossip := make([]*chain.Transaction, 0, len(txs))
@@ -120,21 +221,28 @@ func (g *Proposer) sendTxs(ctx context.Context, txs []*chain.Transaction) error
 		}
 
 		if len(toGossip) == 0 {
-			g.vm.Logger().Debug("nothing to gossip", zap.Stringer("node", proposer))
+			g.vm.Logger().Debug("nothing to gossip", zap.Stringer("node", peer))
 			continue
 		}
 
-		// TODO: cache marshalization
-		actionRegistry, authRegistry := g.vm.Registry()
-		b, err := chain.MarshalTxs(toGossip, actionRegistry, authRegistry)
-		if err != nil {
-			return err
+		var b []byte
+		var err error
+		if useDigest {
+			b = marshalDigest(msgDigest, toGossip)
+		} else {
+			// TODO: cache marshalization
+			actionRegistry, authRegistry := g.vm.Registry()
+			b, err = chain.MarshalTxs(toGossip, actionRegistry, authRegistry)
+			if err != nil {
+				return err
+			}
+			b = append([]byte{msgTxs}, b...)
 		}
 
-		if err := g.appSender.SendAppGossipSpecific(ctx, set.Set[ids.NodeID]{proposer: {}}, b); err != nil {
+		if err := g.appSender.SendAppGossipSpecific(ctx, set.Set[ids.NodeID]{peer: {}}, b); err != nil {
 			g.vm.Logger().Warn(
 				"GossipTxs failed",
-				zap.Stringer("node", proposer),
+				zap.Stringer("node", peer),
 				zap.Error(err),
 			)
 			return err
@@ -143,6 +251,32 @@ func (g *Proposer) sendTxs(ctx context.Context, txs []*chain.Transaction) error
 	return nil
 }
 
+// marshalDigest encodes a [msgDigest] or [msgWant] envelope: a type byte
+// followed by the raw concatenation of tx IDs, letting the receiver pull
+// only the bodies it doesn't already have.
+func marshalDigest(msgType byte, txs []*chain.Transaction) []byte {
+	b := make([]byte, 1, 1+len(txs)*consts.IDLen)
+	b[0] = msgType
+	for _, tx := range txs {
+		id := tx.ID()
+		b = append(b, id[:]...)
+	}
+	return b
+}
+
+func unmarshalIDs(b []byte) ([]ids.ID, error) {
+	if len(b)%consts.IDLen != 0 {
+		return nil, errInvalidDigest
+	}
+	out := make([]ids.ID, 0, len(b)/consts.IDLen)
+	for i := 0; i < len(b); i += consts.IDLen {
+		var id ids.ID
+		copy(id[:], b[i:i+consts.IDLen])
+		out = append(out, id)
+	}
+	return out, nil
+}
+
 // Triggers "AppGossip" on the pending transactions in the mempool.
 // "force" is true to re-gossip whether recently gossiped or not
 func (g *Proposer) TriggerGossip(ctx context.Context) error {
@@ -184,6 +318,12 @@ func (g *Proposer) TriggerGossip(ctx context.Context) error {
 				return true, false, false, nil
 			}
 
+			// Remove txs that have been superseded by a conflicting
+			// replacement (e.g. a cancel/replace broadcast elsewhere)
+			if _, conflicted := g.conflictedTxs.Get(next.ID()); conflicted {
+				return true, false, false, nil
+			}
+
 			// Don't gossip txs that are about to expire
 			life := next.Base.Timestamp - now
 			if life < g.cfg.GossipMinLife {
@@ -220,6 +360,8 @@ func (g *Proposer) TriggerGossip(ctx context.Context) error {
 			}
 			txs = append(txs, next)
 			totalUnits += units
+			g.markConflicts(next)
+			g.txCache.Put(next.ID(), next)
 			return len(txs) < r.GetMaxBlockTxs(), true, false, nil
 		},
 	)
@@ -237,6 +379,99 @@ func (g *Proposer) TriggerGossip(ctx context.Context) error {
 }
 
 func (g *Proposer) HandleAppGossip(ctx context.Context, nodeID ids.NodeID, msg []byte) error {
+	if len(msg) == 0 {
+		g.penalizeUnmarshal(nodeID)
+		return nil
+	}
+
+	switch msgType, body := msg[0], msg[1:]; msgType {
+	case msgTxs:
+		return g.handleTxsMsg(ctx, nodeID, body)
+	case msgDigest:
+		return g.handleDigestMsg(ctx, nodeID, body)
+	case msgWant:
+		return g.handleWantMsg(ctx, nodeID, body)
+	default:
+		g.vm.Logger().Warn(
+			"received gossip with unknown message type",
+			zap.Stringer("peerID", nodeID), zap.Uint8("type", msgType),
+		)
+		g.penalizeUnmarshal(nodeID)
+		return nil
+	}
+}
+
+// handleDigestMsg processes an IHAVE-style advertisement of tx IDs a peer
+// already has, and IWANTs back whichever ones we don't.
+func (g *Proposer) handleDigestMsg(ctx context.Context, nodeID ids.NodeID, body []byte) error {
+	have, err := unmarshalIDs(body)
+	if err != nil {
+		g.vm.Logger().Warn("received invalid digest", zap.Stringer("peerID", nodeID), zap.Error(err))
+		g.penalizeUnmarshal(nodeID)
+		return nil
+	}
+
+	missing := make([][]byte, 0, len(have))
+	for _, id := range have {
+		if _, ok := g.receivedTxs.Get(id); ok {
+			continue
+		}
+		if _, ok := g.txCache.Get(id); ok {
+			continue
+		}
+		idCopy := id
+		missing = append(missing, idCopy[:])
+	}
+	if len(missing) == 0 {
+		return nil
+	}
+
+	b := make([]byte, 1, 1+len(missing)*consts.IDLen)
+	b[0] = msgWant
+	for _, id := range missing {
+		b = append(b, id...)
+	}
+	if err := g.appSender.SendAppGossipSpecific(ctx, set.Set[ids.NodeID]{nodeID: {}}, b); err != nil {
+		g.vm.Logger().Warn("IWANT failed", zap.Stringer("node", nodeID), zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+// handleWantMsg serves an IWANT request by sending back the full bodies
+// of whichever requested tx IDs we still have in txCache.
+func (g *Proposer) handleWantMsg(ctx context.Context, nodeID ids.NodeID, body []byte) error {
+	want, err := unmarshalIDs(body)
+	if err != nil {
+		g.vm.Logger().Warn("received invalid want", zap.Stringer("peerID", nodeID), zap.Error(err))
+		g.penalizeUnmarshal(nodeID)
+		return nil
+	}
+
+	txs := make([]*chain.Transaction, 0, len(want))
+	for _, id := range want {
+		if tx, ok := g.txCache.Get(id); ok {
+			txs = append(txs, tx)
+		}
+	}
+	if len(txs) == 0 {
+		return nil
+	}
+
+	actionRegistry, authRegistry := g.vm.Registry()
+	b, err := chain.MarshalTxs(txs, actionRegistry, authRegistry)
+	if err != nil {
+		return err
+	}
+	b = append([]byte{msgTxs}, b...)
+	if err := g.appSender.SendAppGossipSpecific(ctx, set.Set[ids.NodeID]{nodeID: {}}, b); err != nil {
+		g.vm.Logger().Warn("serving IWANT failed", zap.Stringer("node", nodeID), zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+func (g *Proposer) handleTxsMsg(ctx context.Context, nodeID ids.NodeID, msg []byte) error {
 	r := g.vm.Rules(time.Now().Unix())
 	actionRegistry, authRegistry := g.vm.Registry()
 	txs, err := chain.UnmarshalTxs(msg, r.GetMaxBlockTxs(), actionRegistry, authRegistry)
@@ -246,6 +481,7 @@ func (g *Proposer) HandleAppGossip(ctx context.Context, nodeID ids.NodeID, msg [
 			zap.Stringer("peerID", nodeID),
 			zap.Error(err),
 		)
+		g.penalizeUnmarshal(nodeID)
 		return nil
 	}
 
@@ -269,19 +505,42 @@ func (g *Proposer) HandleAppGossip(ctx context.Context, nodeID ids.NodeID, msg [
 	}
 
 	// Add incoming transactions to our caches to prevent useless gossip
+	toSubmit := make([]*chain.Transaction, 0, len(txs))
 	for _, tx := range txs {
 		if c != nil {
 			c.Put(tx.ID(), struct{}{})
 		}
-		g.receivedTxs.Put(tx.ID(), struct{}{})
+
+		// Score [nodeID]: full credit for being first to deliver a tx we
+		// hadn't seen, partial (age-decayed) credit for a duplicate. Only
+		// the first sighting's timestamp is kept so the age reflects how
+		// stale the relay actually was.
+		if firstSeen, seen := g.receivedTxs.Get(tx.ID()); seen {
+			g.creditDuplicate(nodeID, time.Since(firstSeen))
+		} else {
+			g.creditDelivered(nodeID)
+			g.receivedTxs.Put(tx.ID(), time.Now())
+		}
+		g.markConflicts(tx)
+		g.txCache.Put(tx.ID(), tx)
+
+		// Reject txs that were already superseded by a conflicting
+		// replacement rather than letting them reach mempool admission.
+		if _, conflicted := g.conflictedTxs.Get(tx.ID()); conflicted {
+			continue
+		}
+		toSubmit = append(toSubmit, tx)
 	}
 
 	// Submit incoming gossip to mempool
 	start := time.Now()
-	for _, err := range g.vm.Submit(ctx, true, txs) {
+	for _, err := range g.vm.Submit(ctx, true, toSubmit) {
 		if err == nil || errors.Is(err, chain.ErrDuplicateTx) {
 			continue
 		}
+		// A tx that fails submission (e.g. rejected by PreExecute) is a
+		// sign [nodeID] is relaying invalid or stale gossip.
+		g.penalizeInvalid(nodeID)
 		g.vm.Logger().Debug(
 			"failed to submit gossiped txs",
 			zap.Stringer("nodeID", nodeID), zap.Error(err),
@@ -289,7 +548,7 @@ func (g *Proposer) HandleAppGossip(ctx context.Context, nodeID ids.NodeID, msg [
 	}
 	g.vm.Logger().Info(
 		"submitted gossipped transactions",
-		zap.Int("txs", len(txs)),
+		zap.Int("txs", len(toSubmit)),
 		zap.Stringer("nodeID", nodeID), zap.Duration("t", time.Since(start)),
 	)
 
@@ -312,6 +571,12 @@ func (g *Proposer) Run(appSender common.AppSender) {
 		case <-t.C:
 			tctx := context.Background()
 
+			// Age out old behavior and reshape the mesh before deciding
+			// whether to gossip this tick.
+			g.decayScores()
+			g.pruneMesh()
+			g.graftMesh(tctx)
+
 			// If soon to be proposer, don't gossip
 			proposers, err := g.vm.Proposers(
 				tctx,
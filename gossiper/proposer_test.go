@@ -0,0 +1,101 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package gossiper
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/engine/common"
+	"github.com/ava-labs/avalanchego/trace"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/ava-labs/avalanchego/utils/set"
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeVM implements VM with just enough behavior for the gossip-send path;
+// every method beyond NodeID/Tracer/Logger is unreached as long as the
+// mesh is already at GossipMeshTargetDegree when sendTxs is exercised.
+type fakeVM struct {
+	self ids.NodeID
+}
+
+func (f *fakeVM) NodeID() ids.NodeID  { return f.self }
+func (f *fakeVM) ChainID() ids.ID     { return ids.Empty }
+func (f *fakeVM) Tracer() trace.Tracer { return trace.Noop() }
+func (f *fakeVM) Logger() logging.Logger { return logging.NoLog{} }
+
+func (f *fakeVM) Registry() (chain.ActionRegistry, chain.AuthRegistry) {
+	return chain.ActionRegistry{}, chain.AuthRegistry{}
+}
+
+func (f *fakeVM) Rules(int64) chain.Rules { return nil }
+
+func (f *fakeVM) PreferredBlock(context.Context) (*chain.Block, error) { return nil, nil }
+
+func (f *fakeVM) Proposers(context.Context, int, int) (set.Set[ids.NodeID], error) {
+	return nil, nil
+}
+
+func (f *fakeVM) IsValidator(context.Context, ids.NodeID) (bool, error) { return false, nil }
+
+func (f *fakeVM) Submit(context.Context, bool, []*chain.Transaction) []error { return nil }
+
+func (f *fakeVM) Mempool() Mempool        { return nil }
+func (f *fakeVM) StopChan() chan struct{} { return nil }
+
+// fakeAppSender records every SendAppGossipSpecific payload so a test can
+// assert on the message type byte without a real network.
+type fakeAppSender struct {
+	gossiped [][]byte
+}
+
+func (*fakeAppSender) SendCrossChainAppRequest(context.Context, ids.ID, uint32, []byte) error {
+	return nil
+}
+func (*fakeAppSender) SendCrossChainAppResponse(context.Context, ids.ID, uint32, []byte) error {
+	return nil
+}
+func (*fakeAppSender) SendAppRequest(context.Context, set.Set[ids.NodeID], uint32, []byte) error {
+	return nil
+}
+func (*fakeAppSender) SendAppResponse(context.Context, ids.NodeID, uint32, []byte) error {
+	return nil
+}
+func (*fakeAppSender) SendAppGossip(context.Context, []byte) error { return nil }
+func (f *fakeAppSender) SendAppGossipSpecific(_ context.Context, _ set.Set[ids.NodeID], b []byte) error {
+	f.gossiped = append(f.gossiped, b)
+	return nil
+}
+
+var _ common.AppSender = (*fakeAppSender)(nil)
+
+// TestSendTxsUsesDigestWhenMeshIsFull guards against GossipDigestThreshold
+// regressing to >= GossipMeshTargetDegree, which would make the IHAVE/IWANT
+// digest relay this package exists for unreachable: graftMesh never grows
+// the mesh past GossipMeshTargetDegree, so len(peers) could never clear a
+// higher threshold.
+func TestSendTxsUsesDigestWhenMeshIsFull(t *testing.T) {
+	cfg := DefaultProposerConfig()
+	require.Less(t, cfg.GossipDigestThreshold, cfg.GossipMeshTargetDegree)
+
+	g := NewProposer(&fakeVM{self: ids.GenerateTestNodeID()}, cfg)
+	sender := &fakeAppSender{}
+	g.appSender = sender
+
+	for i := 0; i < cfg.GossipMeshTargetDegree; i++ {
+		g.mesh[ids.GenerateTestNodeID()] = &PeerScore{GraftedAt: time.Now()}
+	}
+
+	tx := &chain.Transaction{Base: &chain.Base{}}
+	require.NoError(t, g.sendTxs(context.Background(), []*chain.Transaction{tx}))
+
+	require.NotEmpty(t, sender.gossiped)
+	for _, msg := range sender.gossiped {
+		require.Equal(t, msgDigest, msg[0])
+	}
+}
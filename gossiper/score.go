@@ -0,0 +1,182 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package gossiper
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/ava-labs/avalanchego/ids"
+)
+
+// PeerScore tracks a single peer's standing in the gossip mesh, following
+// the libp2p GossipSub model: peers earn credit for being first to relay a
+// tx we hadn't seen (and a smaller, age-decayed credit for a fast
+// duplicate) and are penalized for sending malformed or invalid messages.
+// No credit is given when a relayed tx is later included in a block. Peers
+// are pruned from the mesh once their score goes negative or the mesh
+// grows past its high-water mark.
+type PeerScore struct {
+	Score float64
+
+	// GraftedAt is when [Score] was created and gossip to the peer began.
+	// No score is derived from it: time spent in the mesh earns no credit.
+	GraftedAt time.Time
+}
+
+// meshPeers returns the node IDs currently in the gossip mesh, regardless
+// of score.
+func (g *Proposer) meshPeers() []ids.NodeID {
+	g.meshMu.Lock()
+	defer g.meshMu.Unlock()
+
+	peers := make([]ids.NodeID, 0, len(g.mesh))
+	for nodeID := range g.mesh {
+		peers = append(peers, nodeID)
+	}
+	return peers
+}
+
+// creditDelivered rewards [nodeID] for being the first peer to relay a tx
+// we hadn't seen before.
+func (g *Proposer) creditDelivered(nodeID ids.NodeID) {
+	g.adjustScore(nodeID, g.cfg.GossipScoreDeliveredFirst)
+}
+
+// creditDuplicate rewards [nodeID] for relaying a tx we already have, with
+// the credit decaying linearly to 0 as [age] (time since we first saw the
+// tx) approaches [GossipScoreDuplicateWindow]. This still rewards peers
+// that are fast, just less than the original deliverer.
+func (g *Proposer) creditDuplicate(nodeID ids.NodeID, age time.Duration) {
+	window := g.cfg.GossipScoreDuplicateWindow
+	if age >= window {
+		return
+	}
+	frac := 1 - float64(age)/float64(window)
+	g.adjustScore(nodeID, g.cfg.GossipScoreDuplicateCredit*frac)
+}
+
+// penalizeUnmarshal punishes [nodeID] for sending a message we could not
+// even parse.
+func (g *Proposer) penalizeUnmarshal(nodeID ids.NodeID) {
+	g.adjustScore(nodeID, -g.cfg.GossipScoreUnmarshalPenalty)
+}
+
+// penalizeInvalid punishes [nodeID] for relaying a tx that PreExecute
+// rejected.
+func (g *Proposer) penalizeInvalid(nodeID ids.NodeID) {
+	g.adjustScore(nodeID, -g.cfg.GossipScoreInvalidTxPenalty)
+}
+
+// adjustScore only scores peers already grafted into the mesh; it never
+// creates mesh membership. Otherwise any node that sends us gossip (no
+// matter how malformed) could insert itself into the mesh and later get
+// gossiped to via meshPeers, or evict a real grafted proposer out of
+// pruneMesh's GossipMeshMaxDegree cap.
+//
+// This means creditDelivered/creditDuplicate are no-ops for a relayer that
+// isn't currently in the mesh: graftMesh only grafts from Proposers (see
+// its doc comment), so a non-proposer peer that relays a tx first has no
+// path to mesh membership no matter how much delivered-first credit it
+// would otherwise earn. The credit is silently discarded, not queued.
+func (g *Proposer) adjustScore(nodeID ids.NodeID, delta float64) {
+	g.meshMu.Lock()
+	defer g.meshMu.Unlock()
+
+	ps, ok := g.mesh[nodeID]
+	if !ok {
+		return
+	}
+	ps.Score += delta
+}
+
+// decayScores multiplies every mesh member's score by [GossipScoreDecay],
+// run once per GossipInterval so that old behavior matters less over time
+// than recent behavior.
+func (g *Proposer) decayScores() {
+	g.meshMu.Lock()
+	defer g.meshMu.Unlock()
+
+	for _, ps := range g.mesh {
+		ps.Score *= g.cfg.GossipScoreDecay
+	}
+}
+
+// pruneMesh evicts peers with a negative score, or the lowest-scored
+// peers once the mesh grows past GossipMeshMaxDegree. A peer evicted for
+// Score<0 is also backed off from graftMesh for GossipScoreBackoff, so the
+// penalty it earned isn't immediately wiped by a fresh PeerScore{Score:0}
+// on the next graft.
+func (g *Proposer) pruneMesh() {
+	g.meshMu.Lock()
+	defer g.meshMu.Unlock()
+
+	for nodeID, ps := range g.mesh {
+		if ps.Score < 0 {
+			delete(g.mesh, nodeID)
+			g.backoff[nodeID] = time.Now().Add(g.cfg.GossipScoreBackoff)
+		}
+	}
+
+	excess := len(g.mesh) - g.cfg.GossipMeshMaxDegree
+	if excess <= 0 {
+		return
+	}
+	worst := make([]ids.NodeID, 0, len(g.mesh))
+	for nodeID := range g.mesh {
+		worst = append(worst, nodeID)
+	}
+	sortByScoreAsc(worst, g.mesh)
+	for _, nodeID := range worst[:excess] {
+		delete(g.mesh, nodeID)
+	}
+}
+
+// graftMesh tops the mesh back up to GossipMeshTargetDegree, preferring
+// upcoming proposers (who most need our txs). The VM surface this package
+// is handed doesn't expose the full validator set (only Proposers and
+// IsValidator), so proposers are the only grafting source available here.
+func (g *Proposer) graftMesh(ctx context.Context) {
+	g.meshMu.Lock()
+	defer g.meshMu.Unlock()
+
+	if len(g.mesh) >= g.cfg.GossipMeshTargetDegree {
+		return
+	}
+
+	self := g.vm.NodeID()
+	now := time.Now()
+	add := func(nodeID ids.NodeID) {
+		if nodeID == self {
+			return
+		}
+		if _, ok := g.mesh[nodeID]; ok {
+			return
+		}
+		if until, ok := g.backoff[nodeID]; ok {
+			if now.Before(until) {
+				return
+			}
+			delete(g.backoff, nodeID)
+		}
+		if len(g.mesh) >= g.cfg.GossipMeshTargetDegree {
+			return
+		}
+		g.mesh[nodeID] = &PeerScore{GraftedAt: now}
+	}
+
+	if proposers, err := g.vm.Proposers(ctx, g.cfg.GossipProposerDiff, g.cfg.GossipProposerDepth); err == nil {
+		for nodeID := range proposers {
+			add(nodeID)
+		}
+	}
+}
+
+// sortByScoreAsc sorts [nodeIDs] in place, lowest score first.
+func sortByScoreAsc(nodeIDs []ids.NodeID, scores map[ids.NodeID]*PeerScore) {
+	sort.Slice(nodeIDs, func(i, j int) bool {
+		return scores[nodeIDs[i]].Score < scores[nodeIDs[j]].Score
+	})
+}
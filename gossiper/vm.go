@@ -0,0 +1,54 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package gossiper
+
+import (
+	"context"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/engine/common"
+	"github.com/ava-labs/avalanchego/trace"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/ava-labs/avalanchego/utils/set"
+	"github.com/ava-labs/hypersdk/chain"
+)
+
+// Gossiper is the interface the VM drives the gossip loop through.
+type Gossiper interface {
+	Run(appSender common.AppSender)
+	TriggerGossip(ctx context.Context) error
+	HandleAppGossip(ctx context.Context, nodeID ids.NodeID, msg []byte) error
+	Done()
+}
+
+// VM is the subset of the VM this package needs: chain context, the
+// currently preferred block, the validator/proposer view, and mempool
+// access for building what to gossip next.
+type VM interface {
+	NodeID() ids.NodeID
+	ChainID() ids.ID
+	Tracer() trace.Tracer
+	Logger() logging.Logger
+	Registry() (chain.ActionRegistry, chain.AuthRegistry)
+	Rules(t int64) chain.Rules
+	PreferredBlock(ctx context.Context) (*chain.Block, error)
+
+	// Proposers returns the next [depth] proposers starting [diff] slots
+	// from now, per the ProposerVM windowing scheme.
+	Proposers(ctx context.Context, diff int, depth int) (set.Set[ids.NodeID], error)
+	IsValidator(ctx context.Context, nodeID ids.NodeID) (bool, error)
+
+	Submit(ctx context.Context, verify bool, txs []*chain.Transaction) []error
+	Mempool() Mempool
+	StopChan() chan struct{}
+}
+
+// Mempool is the subset of the VM's mempool the gossiper needs to select
+// and build a batch of transactions to gossip.
+type Mempool interface {
+	Build(
+		ctx context.Context,
+		f func(ctx context.Context, next *chain.Transaction) (cont bool, restore bool, removeAcct bool, err error),
+	) error
+}